@@ -0,0 +1,149 @@
+package libdeploy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+type Codec interface {
+	Decode(r io.Reader, c *Config) error
+	Encode(w io.Writer, c Config) error
+}
+
+var codecs = map[string]Codec{}
+
+func RegisterCodec(format string, codec Codec) {
+	codecs[format] = codec
+}
+
+func init() {
+	RegisterCodec("toml", tomlCodec{})
+	RegisterCodec("json", jsonCodec{})
+	RegisterCodec("env", envCodec{})
+}
+
+type tomlCodec struct{}
+
+func (tomlCodec) Decode(r io.Reader, c *Config) error {
+	_, err := toml.DecodeReader(r, c)
+	return err
+}
+
+func (tomlCodec) Encode(w io.Writer, c Config) error {
+	return toml.NewEncoder(w).Encode(c)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Decode(r io.Reader, c *Config) error {
+	return json.NewDecoder(r).Decode(c)
+}
+
+func (jsonCodec) Encode(w io.Writer, c Config) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// envCodec reads/writes a flat dotenv-style KEY=value format. Nesting is
+// not supported; every key becomes a single top-level entry.
+type envCodec struct{}
+
+func (envCodec) Decode(r io.Reader, c *Config) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid env line: %q", line)
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value := strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+		if err := c.Set(value, key); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (envCodec) Encode(w io.Writer, c Config) error {
+	for k, v := range c {
+		if _, err := fmt.Fprintf(w, "%s=%v\n", k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ReadConfigAs(format string, r io.Reader) (Config, error) {
+	codec, ok := codecs[format]
+	if !ok {
+		return nil, fmt.Errorf("no codec registered for format %q", format)
+	}
+
+	c := NewConfig()
+	if err := codec.Decode(r, &c); err != nil {
+		return nil, ConfigError{Code: ErrCodeDecodeFailed, Path: []string{format}, Cause: err}
+	}
+
+	return c, nil
+}
+
+// ReadFile loads a Config from path, picking a codec by file extension.
+func ReadFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	format := strings.TrimPrefix(filepath.Ext(path), ".")
+	switch format {
+	case "":
+		format = "toml"
+	case "yml":
+		format = "yaml"
+	}
+
+	return ReadConfigAs(format, f)
+}
+
+// Merge layers other on top of c: maps merge recursively, scalars and
+// slices from other replace the value in c.
+func (c Config) Merge(other Config) {
+	mergeMaps(map[string]interface{}(c), map[string]interface{}(other))
+}
+
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		sv, ok := asQueryMap(v)
+		if !ok {
+			dst[k] = v
+			continue
+		}
+
+		switch existing := dst[k].(type) {
+		case map[string]interface{}:
+			mergeMaps(existing, sv)
+		case Config:
+			merged := map[string]interface{}(existing)
+			mergeMaps(merged, sv)
+			dst[k] = merged
+		default:
+			dst[k] = sv
+		}
+	}
+}
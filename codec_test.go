@@ -0,0 +1,86 @@
+package libdeploy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadConfigAsWrapsDecodeFailure(t *testing.T) {
+	_, err := ReadConfigAs("json", strings.NewReader("not json"))
+	if err == nil {
+		t.Fatal("expected a decode error, got nil")
+	}
+	if !errors.Is(err, ErrDecodeFailed) {
+		t.Errorf("expected errors.Is(err, ErrDecodeFailed) to be true, got %v", err)
+	}
+}
+
+func TestReadConfigAsUnknownFormat(t *testing.T) {
+	_, err := ReadConfigAs("yaml", strings.NewReader(""))
+	if err == nil {
+		t.Fatal("expected an error for an unregistered format")
+	}
+}
+
+func TestMergeNestedMaps(t *testing.T) {
+	base := Config{
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": int64(5432),
+		},
+	}
+	override := Config{
+		"db": map[string]interface{}{
+			"port": int64(5433),
+		},
+	}
+
+	base.Merge(override)
+
+	host, err := base.GetString("db", "host")
+	if err != nil {
+		t.Fatalf("GetString(\"db\", \"host\") returned error: %v", err)
+	}
+	if host != "localhost" {
+		t.Errorf("GetString(\"db\", \"host\") = %q, want %q (merge should keep untouched keys)", host, "localhost")
+	}
+
+	port, err := base.GetInt("db", "port")
+	if err != nil {
+		t.Fatalf("GetInt(\"db\", \"port\") returned error: %v", err)
+	}
+	if port != 5433 {
+		t.Errorf("GetInt(\"db\", \"port\") = %d, want 5433 (later source should win)", port)
+	}
+}
+
+func TestMergeScalarReplacesScalar(t *testing.T) {
+	base := Config{"name": "base"}
+	override := Config{"name": "override"}
+
+	base.Merge(override)
+
+	name, err := base.GetString("name")
+	if err != nil {
+		t.Fatalf("GetString(\"name\") returned error: %v", err)
+	}
+	if name != "override" {
+		t.Errorf("GetString(\"name\") = %q, want %q", name, "override")
+	}
+}
+
+func TestMergeSliceReplacesRatherThanAppends(t *testing.T) {
+	base := Config{"tags": []interface{}{"a", "b"}}
+	override := Config{"tags": []interface{}{"c"}}
+
+	base.Merge(override)
+
+	tags, err := base.GetSlice("tags")
+	if err != nil {
+		t.Fatalf("GetSlice(\"tags\") returned error: %v", err)
+	}
+	if len(tags) != 1 || tags[0] != "c" {
+		t.Errorf("GetSlice(\"tags\") = %v, want [c] (override should replace, not append)", tags)
+	}
+}
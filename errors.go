@@ -0,0 +1,103 @@
+package libdeploy
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ErrorCode int
+
+const (
+	ErrCodeNotFound ErrorCode = iota
+	ErrCodeTypeMismatch
+	ErrCodeDecodeFailed
+	ErrCodeRequired
+	ErrCodeValidationFailed
+)
+
+func (c ErrorCode) String() string {
+	switch c {
+	case ErrCodeNotFound:
+		return "NotFound"
+	case ErrCodeTypeMismatch:
+		return "TypeMismatch"
+	case ErrCodeDecodeFailed:
+		return "DecodeFailed"
+	case ErrCodeRequired:
+		return "Required"
+	case ErrCodeValidationFailed:
+		return "ValidationFailed"
+	default:
+		return "Unknown"
+	}
+}
+
+// ConfigError is returned by the Get*/Set family instead of ad-hoc
+// fmt.Errorf strings, so callers can inspect Code, Path and the
+// expected/actual kinds, or errors.Is against the Err* sentinels below.
+type ConfigError struct {
+	Code         ErrorCode
+	Path         []string
+	ExpectedKind string
+	ActualKind   string
+	Cause        error
+}
+
+func (e ConfigError) Error() string {
+	path := strings.Join(e.Path, ".")
+
+	var msg string
+	switch e.Code {
+	case ErrCodeNotFound:
+		msg = fmt.Sprintf("Value for %s not found", path)
+	case ErrCodeRequired:
+		msg = fmt.Sprintf("%s is required, please specify it by adding "+
+			"key -k %s:<value>", path, path)
+	case ErrCodeTypeMismatch:
+		msg = fmt.Sprintf("Error converting %s to %s", path, e.ExpectedKind)
+		if e.ActualKind != "" {
+			msg += fmt.Sprintf(" (got %s)", e.ActualKind)
+		}
+	case ErrCodeDecodeFailed:
+		msg = fmt.Sprintf("failed to decode %s", path)
+	case ErrCodeValidationFailed:
+		msg = fmt.Sprintf("validation failed for %s", path)
+	default:
+		msg = fmt.Sprintf("config error at %s", path)
+	}
+
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+func (e ConfigError) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches on Code alone, so errors.Is(err, ErrNotFound) works
+// regardless of Path/Cause.
+func (e ConfigError) Is(target error) bool {
+	t, ok := target.(ConfigError)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+var (
+	ErrNotFound         = ConfigError{Code: ErrCodeNotFound}
+	ErrTypeMismatch     = ConfigError{Code: ErrCodeTypeMismatch}
+	ErrDecodeFailed     = ConfigError{Code: ErrCodeDecodeFailed}
+	ErrRequired         = ConfigError{Code: ErrCodeRequired}
+	ErrValidationFailed = ConfigError{Code: ErrCodeValidationFailed}
+)
+
+// NotFoundError is kept as an alias so code written against the old
+// type keeps compiling.
+type NotFoundError = ConfigError
+
+func NewNotFoundError(path []string) error {
+	return ConfigError{Code: ErrCodeNotFound, Path: path}
+}
@@ -0,0 +1,410 @@
+package libdeploy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	nameStep stepKind = iota
+	indexStep
+	wildcardStep
+	descendStep
+	unionStep
+	filterStep
+)
+
+type pathStep struct {
+	kind      stepKind
+	name      string
+	index     int
+	names     []string
+	indices   []int
+	filterKey string
+	filterVal string
+}
+
+func parseQuery(expr string) ([]pathStep, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, errors.New("query must start with $")
+	}
+
+	steps := []pathStep{}
+	i := 1
+	for i < len(expr) {
+		switch expr[i] {
+		case '.':
+			if i+1 < len(expr) && expr[i+1] == '.' {
+				steps = append(steps, pathStep{kind: descendStep})
+				i += 2
+				// `..name` / `..*` carries the descend target right after
+				// the dots, with no separating `.` or `[`.
+				if i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+					start := i
+					for i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+						i++
+					}
+					name := expr[start:i]
+					if name == "*" {
+						steps = append(steps, pathStep{kind: wildcardStep})
+					} else {
+						steps = append(steps, pathStep{kind: nameStep, name: name})
+					}
+				}
+				continue
+			}
+			i++
+			start := i
+			for i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+				i++
+			}
+			name := expr[start:i]
+			if name == "" {
+				return nil, errors.New("empty path segment")
+			}
+			if name == "*" {
+				steps = append(steps, pathStep{kind: wildcardStep})
+			} else {
+				steps = append(steps, pathStep{kind: nameStep, name: name})
+			}
+		case '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, errors.New("unterminated [ in query")
+			}
+			content := expr[i+1 : i+end]
+			i += end + 1
+
+			step, err := parseBracket(content)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		default:
+			return nil, fmt.Errorf("unexpected character %q in query", expr[i])
+		}
+	}
+
+	return steps, nil
+}
+
+func parseBracket(content string) (pathStep, error) {
+	content = strings.TrimSpace(content)
+
+	if strings.HasPrefix(content, "?") {
+		return parseFilter(content)
+	}
+
+	if content == "*" {
+		return pathStep{kind: wildcardStep}, nil
+	}
+
+	if strings.Contains(content, ",") {
+		parts := strings.Split(content, ",")
+		names := []string{}
+		indices := []int{}
+		for _, p := range parts {
+			p = strings.TrimSpace(p)
+			if name, ok := unquote(p); ok {
+				names = append(names, name)
+				continue
+			}
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return pathStep{}, fmt.Errorf("invalid union element %q", p)
+			}
+			indices = append(indices, n)
+		}
+		return pathStep{kind: unionStep, names: names, indices: indices}, nil
+	}
+
+	if name, ok := unquote(content); ok {
+		return pathStep{kind: nameStep, name: name}, nil
+	}
+
+	n, err := strconv.Atoi(content)
+	if err != nil {
+		return pathStep{}, fmt.Errorf("invalid index %q", content)
+	}
+	return pathStep{kind: indexStep, index: n}, nil
+}
+
+func parseFilter(content string) (pathStep, error) {
+	content = strings.TrimPrefix(content, "?")
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "(")
+	content = strings.TrimSuffix(content, ")")
+	content = strings.TrimPrefix(content, "@.")
+
+	eq := strings.Index(content, "==")
+	if eq < 0 {
+		return pathStep{}, errors.New("only == filters are supported")
+	}
+
+	key := strings.TrimSpace(content[:eq])
+	val := strings.TrimSpace(content[eq+2:])
+	val = strings.Trim(val, `"'`)
+
+	return pathStep{kind: filterStep, filterKey: key, filterVal: val}, nil
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
+
+func asQueryMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case Config:
+		return map[string]interface{}(m), true
+	default:
+		return nil, false
+	}
+}
+
+func asQuerySlice(v interface{}) ([]interface{}, bool) {
+	s, ok := v.([]interface{})
+	return s, ok
+}
+
+func (c Config) Query(expr string) ([]interface{}, error) {
+	steps, err := parseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	frontier := []interface{}{map[string]interface{}(c)}
+
+	for _, step := range steps {
+		next := []interface{}{}
+
+		switch step.kind {
+		case nameStep:
+			for _, node := range frontier {
+				if m, ok := asQueryMap(node); ok {
+					if v, found := m[step.name]; found {
+						next = append(next, v)
+					}
+				}
+			}
+		case indexStep:
+			for _, node := range frontier {
+				if s, ok := asQuerySlice(node); ok {
+					idx := step.index
+					if idx < 0 {
+						idx += len(s)
+					}
+					if idx >= 0 && idx < len(s) {
+						next = append(next, s[idx])
+					}
+				}
+			}
+		case wildcardStep:
+			for _, node := range frontier {
+				if m, ok := asQueryMap(node); ok {
+					for _, v := range m {
+						next = append(next, v)
+					}
+				}
+				if s, ok := asQuerySlice(node); ok {
+					next = append(next, s...)
+				}
+			}
+		case descendStep:
+			var walk func(interface{})
+			walk = func(n interface{}) {
+				next = append(next, n)
+				if m, ok := asQueryMap(n); ok {
+					for _, v := range m {
+						walk(v)
+					}
+				}
+				if s, ok := asQuerySlice(n); ok {
+					for _, v := range s {
+						walk(v)
+					}
+				}
+			}
+			for _, node := range frontier {
+				if m, ok := asQueryMap(node); ok {
+					for _, v := range m {
+						walk(v)
+					}
+				}
+				if s, ok := asQuerySlice(node); ok {
+					for _, v := range s {
+						walk(v)
+					}
+				}
+			}
+		case unionStep:
+			for _, node := range frontier {
+				if m, ok := asQueryMap(node); ok {
+					for _, key := range step.names {
+						if v, found := m[key]; found {
+							next = append(next, v)
+						}
+					}
+				}
+				if s, ok := asQuerySlice(node); ok {
+					for _, idx := range step.indices {
+						i := idx
+						if i < 0 {
+							i += len(s)
+						}
+						if i >= 0 && i < len(s) {
+							next = append(next, s[i])
+						}
+					}
+				}
+			}
+		case filterStep:
+			for _, node := range frontier {
+				items := []interface{}{}
+				if s, ok := asQuerySlice(node); ok {
+					items = s
+				} else if m, ok := asQueryMap(node); ok {
+					for _, v := range m {
+						items = append(items, v)
+					}
+				}
+				for _, item := range items {
+					m, ok := asQueryMap(item)
+					if !ok {
+						continue
+					}
+					v, found := m[step.filterKey]
+					if found && fmt.Sprintf("%v", v) == step.filterVal {
+						next = append(next, item)
+					}
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	if len(frontier) == 0 {
+		return nil, NewNotFoundError([]string{expr})
+	}
+
+	return frontier, nil
+}
+
+func (c Config) QueryOne(expr string) (interface{}, error) {
+	res, err := c.Query(expr)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+func (c Config) QueryString(expr string) (string, error) {
+	v, err := c.QueryOne(expr)
+	if err != nil {
+		return "", err
+	}
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	default:
+		return "", ConfigError{Code: ErrCodeTypeMismatch, Path: []string{expr}, ExpectedKind: "string", ActualKind: fmt.Sprintf("%T", val)}
+	}
+}
+
+func (c Config) QueryInt(expr string) (int64, error) {
+	v, err := c.QueryOne(expr)
+	if err != nil {
+		return 0, err
+	}
+	switch val := v.(type) {
+	case int:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	default:
+		return 0, ConfigError{Code: ErrCodeTypeMismatch, Path: []string{expr}, ExpectedKind: "int", ActualKind: fmt.Sprintf("%T", val)}
+	}
+}
+
+func (c Config) QueryFloat(expr string) (float64, error) {
+	v, err := c.QueryOne(expr)
+	if err != nil {
+		return 0, err
+	}
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case int:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	default:
+		return 0, ConfigError{Code: ErrCodeTypeMismatch, Path: []string{expr}, ExpectedKind: "float", ActualKind: fmt.Sprintf("%T", val)}
+	}
+}
+
+func (c Config) QueryBool(expr string) (bool, error) {
+	v, err := c.QueryOne(expr)
+	if err != nil {
+		return false, err
+	}
+	switch val := v.(type) {
+	case bool:
+		return val, nil
+	default:
+		return false, ConfigError{Code: ErrCodeTypeMismatch, Path: []string{expr}, ExpectedKind: "bool", ActualKind: fmt.Sprintf("%T", val)}
+	}
+}
+
+func (c Config) QuerySlice(expr string) ([]interface{}, error) {
+	v, err := c.QueryOne(expr)
+	if err != nil {
+		return []interface{}{}, err
+	}
+	switch val := v.(type) {
+	case []interface{}:
+		return val, nil
+	default:
+		return []interface{}{}, ConfigError{Code: ErrCodeTypeMismatch, Path: []string{expr}, ExpectedKind: "slice", ActualKind: fmt.Sprintf("%T", val)}
+	}
+}
+
+func (c Config) QueryStringSlice(expr string) ([]string, error) {
+	s, err := c.QuerySlice(expr)
+	if err != nil {
+		return []string{}, err
+	}
+	sl := []string{}
+	for _, v := range s {
+		str, ok := v.(string)
+		if !ok {
+			return []string{}, ConfigError{Code: ErrCodeTypeMismatch, Path: []string{expr}, ExpectedKind: "string", ActualKind: fmt.Sprintf("%T", v)}
+		}
+		sl = append(sl, str)
+	}
+	return sl, nil
+}
+
+func (c Config) QueryMap(expr string) (map[string]interface{}, error) {
+	v, err := c.QueryOne(expr)
+	if err != nil {
+		return map[string]interface{}{}, err
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return val, nil
+	case Config:
+		return map[string]interface{}(val), nil
+	default:
+		return map[string]interface{}{}, ConfigError{Code: ErrCodeTypeMismatch, Path: []string{expr}, ExpectedKind: "map", ActualKind: fmt.Sprintf("%T", val)}
+	}
+}
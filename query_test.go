@@ -0,0 +1,180 @@
+package libdeploy
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestQueryRecursiveDescent(t *testing.T) {
+	c := Config{
+		"store": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"title": "A", "val": "1"},
+				map[string]interface{}{"title": "B", "val": "2"},
+			},
+			"val": "root",
+		},
+	}
+
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{"$..val", []string{"1", "2", "root"}},
+		{`$..["val"]`, []string{"1", "2", "root"}},
+	}
+
+	for _, tc := range cases {
+		res, err := c.Query(tc.expr)
+		if err != nil {
+			t.Fatalf("Query(%q) returned error: %v", tc.expr, err)
+		}
+
+		got := []string{}
+		for _, v := range res {
+			if s, ok := v.(string); ok {
+				got = append(got, s)
+			}
+		}
+		sort.Strings(got)
+		sort.Strings(tc.want)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Query(%q) = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestQueryRecursiveDescentWildcard(t *testing.T) {
+	c := Config{
+		"a": map[string]interface{}{
+			"b": "1",
+			"c": "2",
+		},
+	}
+
+	res, err := c.Query("$..*")
+	if err != nil {
+		t.Fatalf("Query(\"$..*\") returned error: %v", err)
+	}
+	if len(res) == 0 {
+		t.Fatalf("Query(\"$..*\") returned no results")
+	}
+}
+
+func TestQueryOneAndIndex(t *testing.T) {
+	c := Config{
+		"store": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"title": "A"},
+				map[string]interface{}{"title": "B"},
+			},
+		},
+	}
+
+	title, err := c.QueryString("$.store.book[-1].title")
+	if err != nil {
+		t.Fatalf("QueryString returned error: %v", err)
+	}
+	if title != "B" {
+		t.Errorf("QueryString(\"$.store.book[-1].title\") = %q, want %q", title, "B")
+	}
+}
+
+func TestQueryNotFound(t *testing.T) {
+	c := Config{"a": "1"}
+
+	if _, err := c.Query("$.missing"); err == nil {
+		t.Fatal("expected error for missing path, got nil")
+	}
+}
+
+func TestQueryStringTypeMismatchIsConfigError(t *testing.T) {
+	c := Config{"a": 1}
+
+	_, err := c.QueryString("$.a")
+	if err == nil {
+		t.Fatal("expected a type mismatch error, got nil")
+	}
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected errors.Is(err, ErrTypeMismatch) to be true, got %v", err)
+	}
+}
+
+func TestQueryUnion(t *testing.T) {
+	c := Config{
+		"a": "1",
+		"b": "2",
+		"c": "3",
+	}
+
+	res, err := c.Query(`$["a","c"]`)
+	if err != nil {
+		t.Fatalf("Query(`$[\"a\",\"c\"]`) returned error: %v", err)
+	}
+
+	got := []string{}
+	for _, v := range res {
+		if s, ok := v.(string); ok {
+			got = append(got, s)
+		}
+	}
+	sort.Strings(got)
+	want := []string{"1", "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query(`$[\"a\",\"c\"]`) = %v, want %v", got, want)
+	}
+}
+
+func TestQueryUnionIndices(t *testing.T) {
+	c := Config{
+		"items": []interface{}{"a", "b", "c", "d"},
+	}
+
+	res, err := c.Query("$.items[0,2]")
+	if err != nil {
+		t.Fatalf("Query(\"$.items[0,2]\") returned error: %v", err)
+	}
+
+	got := []string{}
+	for _, v := range res {
+		if s, ok := v.(string); ok {
+			got = append(got, s)
+		}
+	}
+	sort.Strings(got)
+	want := []string{"a", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query(\"$.items[0,2]\") = %v, want %v", got, want)
+	}
+}
+
+func TestQueryFilter(t *testing.T) {
+	c := Config{
+		"store": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"category": "tech", "title": "A"},
+				map[string]interface{}{"category": "fiction", "title": "B"},
+				map[string]interface{}{"category": "tech", "title": "C"},
+			},
+		},
+	}
+
+	res, err := c.Query(`$.store.book[?(@.category=="tech")].title`)
+	if err != nil {
+		t.Fatalf("Query filter returned error: %v", err)
+	}
+
+	got := []string{}
+	for _, v := range res {
+		if s, ok := v.(string); ok {
+			got = append(got, s)
+		}
+	}
+	sort.Strings(got)
+	want := []string{"A", "C"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query filter = %v, want %v", got, want)
+	}
+}
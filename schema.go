@@ -0,0 +1,297 @@
+package libdeploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/BurntSushi/toml"
+)
+
+type Kind string
+
+const (
+	KindString Kind = "string"
+	KindInt    Kind = "int"
+	KindFloat  Kind = "float"
+	KindBool   Kind = "bool"
+	KindSlice  Kind = "slice"
+	KindMap    Kind = "map"
+)
+
+// Schema describes the expected shape of a single config path, and
+// recursively the shape of its children for KindMap/KindSlice.
+type Schema struct {
+	Kind     Kind              `json:"kind" toml:"kind"`
+	Required bool              `json:"required" toml:"required"`
+	Default  interface{}       `json:"default,omitempty" toml:"default,omitempty"`
+	Enum     []interface{}     `json:"enum,omitempty" toml:"enum,omitempty"`
+	Min      *float64          `json:"min,omitempty" toml:"min,omitempty"`
+	Max      *float64          `json:"max,omitempty" toml:"max,omitempty"`
+	Pattern  string            `json:"pattern,omitempty" toml:"pattern,omitempty"`
+	Fields   map[string]Schema `json:"fields,omitempty" toml:"fields,omitempty"`
+	Items    *Schema           `json:"items,omitempty" toml:"items,omitempty"`
+}
+
+// LoadSchema decodes a Schema from a TOML or JSON document.
+func LoadSchema(r io.Reader, format string) (Schema, error) {
+	var s Schema
+	switch format {
+	case "json":
+		err := json.NewDecoder(r).Decode(&s)
+		return s, err
+	case "toml":
+		_, err := toml.DecodeReader(r, &s)
+		return s, err
+	default:
+		return s, fmt.Errorf("unsupported schema format %q", format)
+	}
+}
+
+type ValidationErrorKind int
+
+const (
+	Missing ValidationErrorKind = iota
+	TypeMismatch
+	OutOfRange
+	UnknownKey
+)
+
+func (k ValidationErrorKind) String() string {
+	switch k {
+	case Missing:
+		return "Missing"
+	case TypeMismatch:
+		return "TypeMismatch"
+	case OutOfRange:
+		return "OutOfRange"
+	case UnknownKey:
+		return "UnknownKey"
+	default:
+		return "Unknown"
+	}
+}
+
+type ValidationError struct {
+	Path  string
+	Kind  ValidationErrorKind
+	Value interface{}
+	msg   string
+}
+
+func (e ValidationError) Error() string {
+	return e.msg
+}
+
+// Is lets callers check errors.Is(err, ErrValidationFailed) against a
+// ValidationError, the same way ConfigError's sentinels work.
+func (e ValidationError) Is(target error) bool {
+	ce, ok := target.(ConfigError)
+	if !ok {
+		return false
+	}
+	return ce.Code == ErrCodeValidationFailed
+}
+
+func (c Config) ValidateSchema(s Schema) []error {
+	errs := []error{}
+	validateNode(map[string]interface{}(c), s, "", &errs)
+	return errs
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func validateNode(node interface{}, s Schema, path string, errs *[]error) {
+	if node == nil {
+		if s.Required {
+			*errs = append(*errs, ValidationError{
+				Path: path,
+				Kind: Missing,
+				msg:  fmt.Sprintf("%s is required", path),
+			})
+		}
+		return
+	}
+
+	kind := s.Kind
+	if kind == "" && len(s.Fields) > 0 {
+		kind = KindMap
+	}
+
+	switch kind {
+	case KindString:
+		v, ok := node.(string)
+		if !ok {
+			typeMismatch(path, kind, node, errs)
+			return
+		}
+		if v == REQUIRED {
+			*errs = append(*errs, ValidationError{
+				Path: path,
+				Kind: Missing,
+				msg:  fmt.Sprintf("%s is required", path),
+			})
+			return
+		}
+		if s.Pattern != "" {
+			matched, err := regexp.MatchString(s.Pattern, v)
+			if err != nil || !matched {
+				*errs = append(*errs, ValidationError{
+					Path:  path,
+					Kind:  OutOfRange,
+					Value: v,
+					msg:   fmt.Sprintf("%s value %q does not match pattern %q", path, v, s.Pattern),
+				})
+			}
+		}
+		checkEnum(path, v, s.Enum, errs)
+	case KindInt:
+		n, ok := toFloat(node)
+		if !ok {
+			typeMismatch(path, kind, node, errs)
+			return
+		}
+		checkRange(path, n, s, errs)
+		checkEnum(path, node, s.Enum, errs)
+	case KindFloat:
+		n, ok := toFloat(node)
+		if !ok {
+			typeMismatch(path, kind, node, errs)
+			return
+		}
+		checkRange(path, n, s, errs)
+		checkEnum(path, node, s.Enum, errs)
+	case KindBool:
+		if _, ok := node.(bool); !ok {
+			typeMismatch(path, kind, node, errs)
+		}
+	case KindSlice:
+		v, ok := node.([]interface{})
+		if !ok {
+			typeMismatch(path, kind, node, errs)
+			return
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				validateNode(item, *s.Items, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case KindMap:
+		m, ok := asQueryMap(node)
+		if !ok {
+			typeMismatch(path, kind, node, errs)
+			return
+		}
+		for key, sub := range s.Fields {
+			validateNode(m[key], sub, joinPath(path, key), errs)
+		}
+		for key := range m {
+			if _, known := s.Fields[key]; !known {
+				*errs = append(*errs, ValidationError{
+					Path: joinPath(path, key),
+					Kind: UnknownKey,
+					msg:  fmt.Sprintf("%s is not a known key", joinPath(path, key)),
+				})
+			}
+		}
+	}
+}
+
+func typeMismatch(path string, kind Kind, value interface{}, errs *[]error) {
+	*errs = append(*errs, ValidationError{
+		Path:  path,
+		Kind:  TypeMismatch,
+		Value: value,
+		msg:   fmt.Sprintf("%s expected %s, got %T", path, kind, value),
+	})
+}
+
+func checkRange(path string, n float64, s Schema, errs *[]error) {
+	if s.Min != nil && n < *s.Min {
+		*errs = append(*errs, ValidationError{
+			Path:  path,
+			Kind:  OutOfRange,
+			Value: n,
+			msg:   fmt.Sprintf("%s value %v is below minimum %v", path, n, *s.Min),
+		})
+	}
+	if s.Max != nil && n > *s.Max {
+		*errs = append(*errs, ValidationError{
+			Path:  path,
+			Kind:  OutOfRange,
+			Value: n,
+			msg:   fmt.Sprintf("%s value %v is above maximum %v", path, n, *s.Max),
+		})
+	}
+}
+
+func checkEnum(path string, v interface{}, enum []interface{}, errs *[]error) {
+	if len(enum) == 0 {
+		return
+	}
+	for _, allowed := range enum {
+		if fmt.Sprintf("%v", allowed) == fmt.Sprintf("%v", v) {
+			return
+		}
+	}
+	*errs = append(*errs, ValidationError{
+		Path:  path,
+		Kind:  OutOfRange,
+		Value: v,
+		msg:   fmt.Sprintf("%s value %v is not one of %v", path, v, enum),
+	})
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// ApplyDefaults fills missing paths in c with the schema's declared
+// defaults, recursing into nested map schemas, before Validation runs.
+func (c Config) ApplyDefaults(s Schema) {
+	applyDefaults(map[string]interface{}(c), s)
+}
+
+func applyDefaults(m map[string]interface{}, s Schema) {
+	for key, sub := range s.Fields {
+		kind := sub.Kind
+		if kind == "" && len(sub.Fields) > 0 {
+			kind = KindMap
+		}
+
+		if v, exists := m[key]; exists {
+			if kind == KindMap {
+				if vm, ok := asQueryMap(v); ok {
+					applyDefaults(vm, sub)
+				}
+			}
+			continue
+		}
+
+		if sub.Default != nil {
+			m[key] = sub.Default
+			continue
+		}
+
+		if kind == KindMap && len(sub.Fields) > 0 {
+			child := map[string]interface{}{}
+			applyDefaults(child, sub)
+			m[key] = child
+		}
+	}
+}
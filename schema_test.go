@@ -0,0 +1,140 @@
+package libdeploy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateSchemaInfersMapKindFromFields(t *testing.T) {
+	s := Schema{
+		Fields: map[string]Schema{
+			"port": {Kind: KindInt, Required: true},
+		},
+	}
+
+	errs := Config{"port": "notanumber"}.ValidateSchema(s)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 validation error, got %d: %v", len(errs), errs)
+	}
+	ve, ok := errs[0].(ValidationError)
+	if !ok {
+		t.Fatalf("expected ValidationError, got %T", errs[0])
+	}
+	if ve.Kind != TypeMismatch {
+		t.Errorf("expected TypeMismatch, got %v", ve.Kind)
+	}
+}
+
+func TestValidateSchemaMissingRequired(t *testing.T) {
+	s := Schema{
+		Fields: map[string]Schema{
+			"port": {Kind: KindInt, Required: true},
+		},
+	}
+
+	errs := Config{}.ValidateSchema(s)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 validation error, got %d: %v", len(errs), errs)
+	}
+	ve, ok := errs[0].(ValidationError)
+	if !ok {
+		t.Fatalf("expected ValidationError, got %T", errs[0])
+	}
+	if ve.Kind != Missing {
+		t.Errorf("expected Missing, got %v", ve.Kind)
+	}
+}
+
+func TestValidateSchemaValid(t *testing.T) {
+	s := Schema{
+		Fields: map[string]Schema{
+			"port": {Kind: KindInt, Required: true},
+		},
+	}
+
+	errs := Config{"port": int64(8080)}.ValidateSchema(s)
+	if len(errs) != 0 {
+		t.Fatalf("expected no validation errors, got %v", errs)
+	}
+}
+
+func TestValidateSchemaErrorIsErrValidationFailed(t *testing.T) {
+	s := Schema{
+		Fields: map[string]Schema{
+			"port": {Kind: KindInt, Required: true},
+		},
+	}
+
+	errs := Config{}.ValidateSchema(s)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 validation error, got %d: %v", len(errs), errs)
+	}
+	if !errors.Is(errs[0], ErrValidationFailed) {
+		t.Errorf("expected errors.Is(err, ErrValidationFailed) to be true")
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	s := Schema{
+		Fields: map[string]Schema{
+			"port": {Kind: KindInt, Default: int64(8080)},
+		},
+	}
+
+	c := Config{}
+	c.ApplyDefaults(s)
+
+	v, err := c.GetInt("port")
+	if err != nil {
+		t.Fatalf("GetInt(\"port\") returned error: %v", err)
+	}
+	if v != 8080 {
+		t.Errorf("GetInt(\"port\") = %d, want 8080", v)
+	}
+}
+
+func TestApplyDefaultsInfersMapKindFromFields(t *testing.T) {
+	s := Schema{
+		Fields: map[string]Schema{
+			"db": {
+				Fields: map[string]Schema{
+					"port": {Kind: KindInt, Default: int64(5432)},
+				},
+			},
+		},
+	}
+
+	c := Config{"db": map[string]interface{}{}}
+	c.ApplyDefaults(s)
+
+	v, err := c.GetInt("db", "port")
+	if err != nil {
+		t.Fatalf("GetInt(\"db\", \"port\") returned error: %v", err)
+	}
+	if v != 5432 {
+		t.Errorf("GetInt(\"db\", \"port\") = %d, want 5432", v)
+	}
+}
+
+func TestApplyDefaultsCreatesMissingSubTable(t *testing.T) {
+	s := Schema{
+		Fields: map[string]Schema{
+			"db": {
+				Fields: map[string]Schema{
+					"port": {Kind: KindInt, Default: int64(5432)},
+				},
+			},
+		},
+	}
+
+	c := Config{}
+	c.ApplyDefaults(s)
+
+	v, err := c.GetInt("db", "port")
+	if err != nil {
+		t.Fatalf("GetInt(\"db\", \"port\") returned error: %v", err)
+	}
+	if v != 5432 {
+		t.Errorf("GetInt(\"db\", \"port\") = %d, want 5432", v)
+	}
+}
@@ -0,0 +1,304 @@
+package libdeploy
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal binds c onto out, which must be a pointer to a struct whose
+// fields carry a `zhash:"path.to.key,required,default=foo"` tag. Embedded
+// structs without their own tag are flattened into the current path. An
+// embedded struct whose type is unexported is skipped like any other
+// unexported field, so its fields are not bound.
+func (c Config) Unmarshal(out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Unmarshal requires a pointer to struct, got %T", out)
+	}
+	return unmarshalStruct(c, v.Elem())
+}
+
+func unmarshalStruct(c Config, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+		fv := v.Field(i)
+		tag := field.Tag.Get("zhash")
+
+		if field.Anonymous && tag == "" && fv.Kind() == reflect.Struct {
+			if err := unmarshalStruct(c, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if tag == "" {
+			continue
+		}
+
+		pathStr, required, defaultVal, hasDefault := parseTag(tag)
+		path := strings.Split(pathStr, ".")
+		raw := c.GetPath(path...)
+
+		if raw == nil {
+			if required {
+				return ConfigError{Code: ErrCodeRequired, Path: path}
+			}
+			if hasDefault {
+				if err := assignDefault(fv, defaultVal); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := assignValue(fv, raw, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseTag(tag string) (path string, required bool, defaultVal string, hasDefault bool) {
+	opts := strings.Split(tag, ",")
+	path = opts[0]
+	for _, opt := range opts[1:] {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "required":
+			required = true
+		case strings.HasPrefix(opt, "default="):
+			defaultVal = strings.TrimPrefix(opt, "default=")
+			hasDefault = true
+		}
+	}
+	return
+}
+
+func assignValue(fv reflect.Value, raw interface{}, path []string) error {
+	switch fv.Kind() {
+	case reflect.Ptr:
+		ptr := reflect.New(fv.Type().Elem())
+		if err := assignValue(ptr.Elem(), raw, path); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return ConfigError{Code: ErrCodeTypeMismatch, Path: path, ExpectedKind: "string", ActualKind: fmt.Sprintf("%T", raw)}
+		}
+		fv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := toInt64(raw)
+		if !ok {
+			return ConfigError{Code: ErrCodeTypeMismatch, Path: path, ExpectedKind: "int", ActualKind: fmt.Sprintf("%T", raw)}
+		}
+		fv.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, ok := toFloat(raw)
+		if !ok {
+			return ConfigError{Code: ErrCodeTypeMismatch, Path: path, ExpectedKind: "float", ActualKind: fmt.Sprintf("%T", raw)}
+		}
+		fv.SetFloat(n)
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return ConfigError{Code: ErrCodeTypeMismatch, Path: path, ExpectedKind: "bool", ActualKind: fmt.Sprintf("%T", raw)}
+		}
+		fv.SetBool(b)
+	case reflect.Struct:
+		m, ok := asQueryMap(raw)
+		if !ok {
+			return ConfigError{Code: ErrCodeTypeMismatch, Path: path, ExpectedKind: "map", ActualKind: fmt.Sprintf("%T", raw)}
+		}
+		ptr := reflect.New(fv.Type())
+		if err := unmarshalStruct(Config(m), ptr.Elem()); err != nil {
+			return err
+		}
+		fv.Set(ptr.Elem())
+	case reflect.Slice:
+		s, ok := raw.([]interface{})
+		if !ok {
+			return ConfigError{Code: ErrCodeTypeMismatch, Path: path, ExpectedKind: "slice", ActualKind: fmt.Sprintf("%T", raw)}
+		}
+		slice := reflect.MakeSlice(fv.Type(), len(s), len(s))
+		for i, item := range s {
+			if err := assignValue(slice.Index(i), item, path); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+	case reflect.Map:
+		if fv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("zhash: unsupported map key kind %s at %s", fv.Type().Key().Kind(), strings.Join(path, "."))
+		}
+		m, ok := asQueryMap(raw)
+		if !ok {
+			return ConfigError{Code: ErrCodeTypeMismatch, Path: path, ExpectedKind: "map", ActualKind: fmt.Sprintf("%T", raw)}
+		}
+		mv := reflect.MakeMapWithSize(fv.Type(), len(m))
+		for k, item := range m {
+			elem := reflect.New(fv.Type().Elem()).Elem()
+			if err := assignValue(elem, item, path); err != nil {
+				return err
+			}
+			mv.SetMapIndex(reflect.ValueOf(k).Convert(fv.Type().Key()), elem)
+		}
+		fv.Set(mv)
+	default:
+		return fmt.Errorf("zhash: unsupported field kind %s at %s", fv.Kind(), strings.Join(path, "."))
+	}
+	return nil
+}
+
+func assignDefault(fv reflect.Value, s string) error {
+	switch fv.Kind() {
+	case reflect.Ptr:
+		ptr := reflect.New(fv.Type().Elem())
+		if err := assignDefault(ptr.Elem(), s); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	default:
+		return fmt.Errorf("zhash: unsupported default for field kind %s", fv.Kind())
+	}
+	return nil
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// MarshalStruct is the inverse of Unmarshal: it walks in's `zhash` tags
+// and produces a Config with the matching paths set.
+func MarshalStruct(in interface{}) (Config, error) {
+	v := reflect.ValueOf(in)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("MarshalStruct requires a struct, got %T", in)
+	}
+
+	c := NewConfig()
+	if err := marshalStruct(c, v); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func marshalStruct(c Config, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fv := v.Field(i)
+		tag := field.Tag.Get("zhash")
+
+		if field.Anonymous && tag == "" && fv.Kind() == reflect.Struct {
+			if err := marshalStruct(c, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if tag == "" {
+			continue
+		}
+
+		pathStr, _, _, _ := parseTag(tag)
+		path := strings.Split(pathStr, ".")
+
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		val, err := marshalValue(fv)
+		if err != nil {
+			return err
+		}
+		if err := c.Set(val, path...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func marshalValue(fv reflect.Value) (interface{}, error) {
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fv.Int(), nil
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), nil
+	case reflect.Bool:
+		return fv.Bool(), nil
+	case reflect.Struct:
+		sub := NewConfig()
+		if err := marshalStruct(sub, fv); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}(sub), nil
+	case reflect.Slice:
+		out := make([]interface{}, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			ev, err := marshalValue(fv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = ev
+		}
+		return out, nil
+	case reflect.Map:
+		out := map[string]interface{}{}
+		for _, key := range fv.MapKeys() {
+			ev, err := marshalValue(fv.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			out[fmt.Sprintf("%v", key.Interface())] = ev
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("zhash: unsupported field kind %s", fv.Kind())
+	}
+}
@@ -0,0 +1,90 @@
+package libdeploy
+
+import "testing"
+
+type DBConfig struct {
+	Host string `zhash:"db.host,required"`
+	Port int    `zhash:"db.port,default=5432"`
+}
+
+type appConfig struct {
+	DBConfig
+	Name  string   `zhash:"name"`
+	Tags  []string `zhash:"tags"`
+	Debug *bool    `zhash:"debug"`
+}
+
+func TestUnmarshalBasic(t *testing.T) {
+	c := Config{
+		"db": map[string]interface{}{
+			"host": "localhost",
+		},
+		"name": "zhash",
+		"tags": []interface{}{"a", "b"},
+	}
+
+	var cfg appConfig
+	if err := c.Unmarshal(&cfg); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	if cfg.Host != "localhost" {
+		t.Errorf("Host = %q, want %q", cfg.Host, "localhost")
+	}
+	if cfg.Port != 5432 {
+		t.Errorf("Port = %d, want default 5432", cfg.Port)
+	}
+	if cfg.Name != "zhash" {
+		t.Errorf("Name = %q, want %q", cfg.Name, "zhash")
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "a" || cfg.Tags[1] != "b" {
+		t.Errorf("Tags = %v, want [a b]", cfg.Tags)
+	}
+	if cfg.Debug != nil {
+		t.Errorf("Debug = %v, want nil", cfg.Debug)
+	}
+}
+
+func TestUnmarshalMissingRequired(t *testing.T) {
+	c := Config{}
+
+	var cfg appConfig
+	err := c.Unmarshal(&cfg)
+	if err == nil {
+		t.Fatal("expected an error for missing required field, got nil")
+	}
+	ce, ok := err.(ConfigError)
+	if !ok {
+		t.Fatalf("expected ConfigError, got %T", err)
+	}
+	if ce.Code != ErrCodeRequired {
+		t.Errorf("Code = %v, want ErrCodeRequired", ce.Code)
+	}
+}
+
+func TestMarshalStructRoundTrip(t *testing.T) {
+	debug := true
+	cfg := appConfig{
+		DBConfig: DBConfig{Host: "localhost", Port: 5432},
+		Name:     "zhash",
+		Tags:     []string{"a", "b"},
+		Debug:    &debug,
+	}
+
+	c, err := MarshalStruct(cfg)
+	if err != nil {
+		t.Fatalf("MarshalStruct returned error: %v", err)
+	}
+
+	var out appConfig
+	if err := c.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal of marshaled config returned error: %v", err)
+	}
+
+	if out.Host != cfg.Host || out.Port != cfg.Port || out.Name != cfg.Name {
+		t.Errorf("round-tripped config = %+v, want %+v", out, cfg)
+	}
+	if out.Debug == nil || *out.Debug != true {
+		t.Errorf("Debug = %v, want pointer to true", out.Debug)
+	}
+}
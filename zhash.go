@@ -3,12 +3,9 @@ package libdeploy
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"strings"
-
-	"github.com/BurntSushi/toml"
 )
 
 const REQUIRED = "[REQUIRED]"
@@ -19,18 +16,6 @@ func NewConfig() Config {
 	return Config{}
 }
 
-type NotFoundError struct {
-	Path []string
-}
-
-func (e NotFoundError) Error() string {
-	return fmt.Sprintf("Value for %s not found", strings.Join(e.Path, "."))
-}
-
-func NewNotFoundError(path []string) error {
-	return NotFoundError{path}
-}
-
 type RequiredError struct {
 	Path string
 }
@@ -41,12 +26,11 @@ func (e RequiredError) Error() string {
 }
 
 func (c *Config) ReadConfig(r io.Reader) error {
-	_, err := toml.DecodeReader(r, &c)
-	return err
+	return codecs["toml"].Decode(r, c)
 }
 
 func (c Config) WriteConfig(w io.Writer) error {
-	return toml.NewEncoder(w).Encode(c)
+	return codecs["toml"].Encode(w, c)
 }
 
 func (c Config) Reader() io.Reader {
@@ -55,11 +39,11 @@ func (c Config) Reader() io.Reader {
 	return &buff
 }
 
-func (c Config) SetPath(value interface{}, path string) {
-	c.Set(value, strings.Split(path, ".")...)
+func (c Config) SetPath(value interface{}, path string) error {
+	return c.Set(value, strings.Split(path, ".")...)
 }
 
-func (c Config) Set(value interface{}, path ...string) {
+func (c Config) Set(value interface{}, path ...string) error {
 	key := ""
 	ptr := map[string]interface{}(c)
 	for i, p := range path {
@@ -69,15 +53,24 @@ func (c Config) Set(value interface{}, path ...string) {
 				ptr = node
 			case Config:
 				ptr = map[string]interface{}(node)
+			case nil:
+				next := map[string]interface{}{}
+				ptr[p] = next
+				ptr = next
 			default:
-				ptr[p] = map[string]interface{}{}
-				ptr = ptr[p].(map[string]interface{})
+				return ConfigError{
+					Code:         ErrCodeTypeMismatch,
+					Path:         path[:i+1],
+					ExpectedKind: "map",
+					ActualKind:   fmt.Sprintf("%T", node),
+				}
 			}
 		}
 		key = p
 	}
 
 	ptr[key] = value
+	return nil
 }
 
 func (c Config) GetPath(path ...string) interface{} {
@@ -107,9 +100,10 @@ func (c Config) GetMap(path ...string) (map[string]interface{}, error) {
 	case map[string]interface{}:
 		return val, nil
 	default:
-		return map[string]interface{}{},
-			errors.New(fmt.Sprintf("Error converting %s to map",
-				strings.Join(path, ".")))
+		return map[string]interface{}{}, ConfigError{
+			Code: ErrCodeTypeMismatch, Path: path,
+			ExpectedKind: "map", ActualKind: fmt.Sprintf("%T", val),
+		}
 	}
 }
 
@@ -122,8 +116,10 @@ func (c Config) GetString(path ...string) (string, error) {
 	case string:
 		return val, nil
 	default:
-		return "", errors.New(fmt.Sprintf("Error converting %s to string",
-			strings.Join(path, ".")))
+		return "", ConfigError{
+			Code: ErrCodeTypeMismatch, Path: path,
+			ExpectedKind: "string", ActualKind: fmt.Sprintf("%T", val),
+		}
 	}
 }
 
@@ -136,9 +132,10 @@ func (c Config) GetSlice(path ...string) ([]interface{}, error) {
 	case []interface{}:
 		return val, nil
 	default:
-		return []interface{}{},
-			errors.New(fmt.Sprintf("Error converting %s to slice",
-				strings.Join(path, ".")))
+		return []interface{}{}, ConfigError{
+			Code: ErrCodeTypeMismatch, Path: path,
+			ExpectedKind: "slice", ActualKind: fmt.Sprintf("%T", val),
+		}
 	}
 }
 
@@ -155,16 +152,18 @@ func (c Config) GetStringSlice(path ...string) ([]string, error) {
 			case string:
 				sl = append(sl, s)
 			default:
-				return []string{}, errors.New(
-					fmt.Sprintf("Error converting %s to string slice",
-						strings.Join(path, ".")))
+				return []string{}, ConfigError{
+					Code: ErrCodeTypeMismatch, Path: path,
+					ExpectedKind: "string", ActualKind: fmt.Sprintf("%T", s),
+				}
 			}
 		}
 		return sl, nil
 	default:
-		return []string{},
-			errors.New(fmt.Sprintf("Error converting %s to slice",
-				strings.Join(path, ".")))
+		return []string{}, ConfigError{
+			Code: ErrCodeTypeMismatch, Path: path,
+			ExpectedKind: "slice", ActualKind: fmt.Sprintf("%T", val),
+		}
 	}
 }
 
@@ -177,8 +176,10 @@ func (c Config) GetBool(path ...string) (bool, error) {
 	case bool:
 		return val, nil
 	default:
-		return false, errors.New(fmt.Sprintf("Error converting %s to bool",
-			strings.Join(path, ".")))
+		return false, ConfigError{
+			Code: ErrCodeTypeMismatch, Path: path,
+			ExpectedKind: "bool", ActualKind: fmt.Sprintf("%T", val),
+		}
 	}
 }
 
@@ -193,8 +194,10 @@ func (c Config) GetInt(path ...string) (int64, error) {
 	case int64:
 		return val, nil
 	default:
-		return 0, errors.New(fmt.Sprintf("Error converting %s to int",
-			strings.Join(path, ".")))
+		return 0, ConfigError{
+			Code: ErrCodeTypeMismatch, Path: path,
+			ExpectedKind: "int", ActualKind: fmt.Sprintf("%T", val),
+		}
 	}
 }
 
@@ -211,8 +214,10 @@ func (c Config) GetFloat(path ...string) (float64, error) {
 	case int64:
 		return float64(val), nil
 	default:
-		return 0, errors.New(fmt.Sprintf("Error converting %s to float",
-			strings.Join(path, ".")))
+		return 0, ConfigError{
+			Code: ErrCodeTypeMismatch, Path: path,
+			ExpectedKind: "float", ActualKind: fmt.Sprintf("%T", val),
+		}
 	}
 }
 